@@ -0,0 +1,115 @@
+package gatekeeper
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrIntrospectionUnsupported is returned by ForceUnlock and ListLocks when
+// the configured Backend doesn't implement IntrospectableBackend.
+var ErrIntrospectionUnsupported = errors.New("gatekeeper: backend does not support introspection")
+
+// Stats summarizes the current lock state, broken down by resource type.
+type Stats struct {
+	TotalLocks     int
+	ByResourceType map[string]int
+	// AcquiredByResourceType is a cumulative, process-lifetime count of
+	// successful acquisitions per resource type, so operators can see
+	// which resource classes are hot even after contention clears.
+	AcquiredByResourceType map[string]int64
+}
+
+// ForceUnlock clears a lock regardless of who holds it or what token they
+// hold it with. It exists for operators: a crashed handler between TryLock
+// and its deferred ReleaseLock leaves a resource unusable until
+// LockTimeout expires, and an operator needs a way to clear that without
+// restarting the process.
+func ForceUnlock(ctx context.Context, resourceType, resourceID string) error {
+	backend, ok := instance.config.Backend.(IntrospectableBackend)
+	if !ok {
+		return ErrIntrospectionUnsupported
+	}
+	return backend.ForceRelease(ctx, resourceType, resourceID)
+}
+
+// ListLocks returns every currently-held lock, optionally restricted to a
+// single resourceType.
+func ListLocks(ctx context.Context, resourceType string) ([]LockInfo, error) {
+	backend, ok := instance.config.Backend.(IntrospectableBackend)
+	if !ok {
+		return nil, ErrIntrospectionUnsupported
+	}
+	return backend.ListLocks(ctx, resourceType)
+}
+
+// GetStats returns a snapshot of the current lock state. It requires an
+// IntrospectableBackend; against one that doesn't support introspection it
+// returns an empty Stats.
+func GetStats(ctx context.Context) Stats {
+	locks, err := ListLocks(ctx, "")
+	if err != nil {
+		return Stats{ByResourceType: map[string]int{}}
+	}
+
+	stats := Stats{ByResourceType: make(map[string]int)}
+	for _, lock := range locks {
+		stats.TotalLocks++
+		stats.ByResourceType[lock.ResourceType]++
+	}
+
+	instance.counterMu.Lock()
+	stats.AcquiredByResourceType = make(map[string]int64, len(instance.acquireCounts))
+	for resourceType, count := range instance.acquireCounts {
+		stats.AcquiredByResourceType[resourceType] = count
+	}
+	instance.counterMu.Unlock()
+
+	return stats
+}
+
+// AdminHandler returns a Fiber handler exposing the admin surface as JSON.
+// Mount it at a single route and protect it behind your own auth
+// middleware, e.g.:
+//
+//	admin.Get("/gatekeeper", gatekeeper.AdminHandler())
+//
+// Behavior is selected by method and query params:
+//   - GET with no "resourceType"   -> overall Stats
+//   - GET with "resourceType"      -> ListLocks for that resource type
+//   - DELETE with "resourceType" and "resourceId" -> ForceUnlock
+func AdminHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		resourceType := c.Query("resourceType")
+		resourceID := c.Query("resourceId")
+
+		switch c.Method() {
+		case fiber.MethodDelete:
+			if resourceType == "" || resourceID == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"message": "resourceType and resourceId are required",
+				})
+			}
+			if err := ForceUnlock(ctx, resourceType, resourceID); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"message": err.Error(),
+				})
+			}
+			return c.SendStatus(fiber.StatusNoContent)
+
+		default:
+			if resourceType != "" {
+				locks, err := ListLocks(ctx, resourceType)
+				if err != nil {
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+						"message": err.Error(),
+					})
+				}
+				return c.JSON(fiber.Map{"locks": locks})
+			}
+			return c.JSON(GetStats(ctx))
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package gatekeeper
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// bareLockBackend wraps memoryBackend but overrides ListLocks and
+// ForceRelease to return ErrIntrospectionUnsupported, so admin operations
+// against it fail with that error.
+type bareLockBackend struct {
+	*memoryBackend
+}
+
+func newBareLockBackend() bareLockBackend {
+	return bareLockBackend{memoryBackend: newMemoryBackend()}
+}
+
+func (b bareLockBackend) ListLocks(ctx context.Context, resourceType string) ([]LockInfo, error) {
+	return nil, ErrIntrospectionUnsupported
+}
+
+func (b bareLockBackend) ForceRelease(ctx context.Context, resourceType, resourceID string) error {
+	return ErrIntrospectionUnsupported
+}
+
+func TestForceUnlockClearsLockHeldByAnotherCaller(t *testing.T) {
+	Setup(WithBackend(newMemoryBackend()), WithSweepInterval(0))
+	defer Shutdown()
+
+	ctx := context.Background()
+	if _, ok, err := TryLock(ctx, "user", "1"); err != nil || !ok {
+		t.Fatalf("TryLock failed: ok=%v err=%v", ok, err)
+	}
+
+	if err := ForceUnlock(ctx, "user", "1"); err != nil {
+		t.Fatalf("ForceUnlock: %v", err)
+	}
+
+	held, err := IsLocked(ctx, "user", "1")
+	if err != nil || held {
+		t.Fatalf("lock still held after ForceUnlock: held=%v err=%v", held, err)
+	}
+}
+
+func adminTestApp() *fiber.App {
+	app := fiber.New()
+	app.All("/admin", AdminHandler())
+	return app
+}
+
+func TestAdminHandlerGetWithNoResourceTypeReturnsStats(t *testing.T) {
+	Setup(WithBackend(newMemoryBackend()), WithSweepInterval(0))
+	defer Shutdown()
+
+	ctx := context.Background()
+	token, ok, err := TryLock(ctx, "user", "1")
+	if err != nil || !ok {
+		t.Fatalf("TryLock failed: ok=%v err=%v", ok, err)
+	}
+	defer ReleaseLock(ctx, "user", "1", token)
+
+	resp, err := adminTestApp().Test(httptest.NewRequest(fiber.MethodGet, "/admin", nil))
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusOK)
+	}
+}
+
+func TestAdminHandlerGetWithResourceTypeReturnsListLocks(t *testing.T) {
+	Setup(WithBackend(newMemoryBackend()), WithSweepInterval(0))
+	defer Shutdown()
+
+	ctx := context.Background()
+	token, ok, err := TryLock(ctx, "user", "1")
+	if err != nil || !ok {
+		t.Fatalf("TryLock failed: ok=%v err=%v", ok, err)
+	}
+	defer ReleaseLock(ctx, "user", "1", token)
+
+	resp, err := adminTestApp().Test(httptest.NewRequest(fiber.MethodGet, "/admin?resourceType=user", nil))
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusOK)
+	}
+}
+
+func TestAdminHandlerDeleteForceUnlocksResource(t *testing.T) {
+	Setup(WithBackend(newMemoryBackend()), WithSweepInterval(0))
+	defer Shutdown()
+
+	ctx := context.Background()
+	if _, ok, err := TryLock(ctx, "user", "1"); err != nil || !ok {
+		t.Fatalf("TryLock failed: ok=%v err=%v", ok, err)
+	}
+
+	resp, err := adminTestApp().Test(httptest.NewRequest(fiber.MethodDelete, "/admin?resourceType=user&resourceId=1", nil))
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusNoContent {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusNoContent)
+	}
+
+	held, err := IsLocked(ctx, "user", "1")
+	if err != nil || held {
+		t.Fatalf("lock still held after DELETE: held=%v err=%v", held, err)
+	}
+}
+
+func TestAdminHandlerDeleteMissingParamsReturnsBadRequest(t *testing.T) {
+	Setup(WithBackend(newMemoryBackend()), WithSweepInterval(0))
+	defer Shutdown()
+
+	resp, err := adminTestApp().Test(httptest.NewRequest(fiber.MethodDelete, "/admin?resourceType=user", nil))
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusBadRequest)
+	}
+}
+
+func TestAdminHandlerDeleteAgainstUnsupportedBackendReturnsServerError(t *testing.T) {
+	Setup(WithBackend(newBareLockBackend()), WithSweepInterval(0))
+	defer Shutdown()
+
+	resp, err := adminTestApp().Test(httptest.NewRequest(fiber.MethodDelete, "/admin?resourceType=user&resourceId=1", nil))
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusInternalServerError)
+	}
+}
+
+func TestAdminHandlerGetWithResourceTypeAgainstUnsupportedBackendReturnsServerError(t *testing.T) {
+	Setup(WithBackend(newBareLockBackend()), WithSweepInterval(0))
+	defer Shutdown()
+
+	resp, err := adminTestApp().Test(httptest.NewRequest(fiber.MethodGet, "/admin?resourceType=user", nil))
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusInternalServerError)
+	}
+}
+
+func TestTryLockWithMetadataSurfacedByListLocks(t *testing.T) {
+	Setup(WithBackend(newMemoryBackend()), WithSweepInterval(0))
+	defer Shutdown()
+
+	ctx := context.Background()
+	meta := map[string]string{"host": "api-7", "requestId": "abc123"}
+
+	token, ok, err := TryLockWithMetadata(ctx, "user", "1", meta)
+	if err != nil || !ok {
+		t.Fatalf("TryLockWithMetadata failed: ok=%v err=%v", ok, err)
+	}
+	defer ReleaseLock(ctx, "user", "1", token)
+
+	locks, err := ListLocks(ctx, "user")
+	if err != nil {
+		t.Fatalf("ListLocks: %v", err)
+	}
+	if len(locks) != 1 {
+		t.Fatalf("len(locks) = %d, want 1", len(locks))
+	}
+	if locks[0].Metadata["host"] != "api-7" || locks[0].Metadata["requestId"] != "abc123" {
+		t.Fatalf("Metadata = %#v, want captured source metadata", locks[0].Metadata)
+	}
+}
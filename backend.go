@@ -0,0 +1,69 @@
+package gatekeeper
+
+import (
+	"context"
+	"time"
+)
+
+// LockBackend is the storage and coordination layer behind the public
+// locking API. The default Config uses an in-process memoryBackend, but
+// WithBackend lets callers swap in a backend that coordinates across
+// multiple Gatekeeper instances (e.g. several Fiber processes behind a
+// load balancer).
+//
+// Implementations must be safe for concurrent use.
+type LockBackend interface {
+	// TryAcquire attempts to take the lock for (resourceType, resourceID)
+	// for the given ttl. On success it returns a token that uniquely
+	// identifies this acquisition; that token must be presented to
+	// Release so a caller can only unlock a lock it actually holds.
+	TryAcquire(ctx context.Context, resourceType, resourceID string, ttl time.Duration) (token string, ok bool, err error)
+
+	// Release gives up the lock for (resourceType, resourceID) if and
+	// only if token matches the one returned by the acquiring TryAcquire
+	// call. Releasing a lock that is already gone, or with a stale
+	// token, is not an error.
+	Release(ctx context.Context, resourceType, resourceID, token string) error
+
+	// IsHeld reports whether (resourceType, resourceID) is currently
+	// locked.
+	IsHeld(ctx context.Context, resourceType, resourceID string) (bool, error)
+}
+
+// IntrospectableBackend is implemented by backends that can enumerate and
+// forcibly clear locks. It's optional: memoryBackend implements it, but a
+// remote backend isn't required to support a cheap full scan. Admin
+// operations degrade gracefully (ErrIntrospectionUnsupported) against a
+// backend that doesn't implement it.
+type IntrospectableBackend interface {
+	// ListLocks returns every currently-held lock. If resourceType is
+	// non-empty, results are restricted to that resource type.
+	ListLocks(ctx context.Context, resourceType string) ([]LockInfo, error)
+
+	// ForceRelease clears a lock regardless of token, for operator use
+	// when a holder crashed between TryLock and ReleaseLock.
+	ForceRelease(ctx context.Context, resourceType, resourceID string) error
+}
+
+// LockInfo describes a single held lock, as reported by ListLocks.
+type LockInfo struct {
+	ResourceType string
+	ResourceID   string
+	Token        string
+	AcquiredAt   time.Time
+	TTLRemaining time.Duration
+	// Metadata is caller-supplied source metadata (e.g. hostname, request
+	// ID) captured at TryLockWithMetadata time, if any was given and the
+	// backend supports it. Nil when no metadata was captured.
+	Metadata map[string]string
+}
+
+// MetadataBackend is implemented by backends that can record caller-
+// supplied metadata alongside a held lock for later introspection via
+// ListLocks. It's optional: memoryBackend implements it, but a remote
+// backend isn't required to. Operations against a backend that doesn't
+// implement it fall back to a plain TryAcquire (no error is returned; the
+// metadata is simply dropped).
+type MetadataBackend interface {
+	TryAcquireWithMetadata(ctx context.Context, resourceType, resourceID string, ttl time.Duration, metadata map[string]string) (token string, ok bool, err error)
+}
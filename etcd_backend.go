@@ -0,0 +1,91 @@
+package gatekeeper
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcd leases only have whole-second TTLs, so a sub-second ttl (e.g. a
+// 500ms LockTimeout) is rounded up to 1s rather than truncated to 0, which
+// etcd would reject or clamp to its own server-side floor. This means the
+// lease can slightly outlive ttl; callers relying on sub-second precision
+// should use a backend that supports it.
+func leaseTTLSeconds(ttl time.Duration) int64 {
+	seconds := int64(ttl / time.Second)
+	if ttl%time.Second != 0 {
+		seconds++
+	}
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+// EtcdBackend is a LockBackend that coordinates across processes using
+// etcd. Acquisition grants a lease for the TTL and writes the key only if
+// it doesn't already exist (CreateRevision == 0); the lock expires on its
+// own once the lease lapses, and release revokes the lease early.
+type EtcdBackend struct {
+	client *clientv3.Client
+}
+
+// NewEtcdBackend wraps an existing *clientv3.Client as a LockBackend.
+func NewEtcdBackend(client *clientv3.Client) *EtcdBackend {
+	return &EtcdBackend{client: client}
+}
+
+func (b *EtcdBackend) key(resourceType, resourceID string) string {
+	return "gatekeeper/" + resourceType + "/" + resourceID
+}
+
+func (b *EtcdBackend) TryAcquire(ctx context.Context, resourceType, resourceID string, ttl time.Duration) (string, bool, error) {
+	lease, err := b.client.Grant(ctx, leaseTTLSeconds(ttl))
+	if err != nil {
+		return "", false, err
+	}
+
+	key := b.key(resourceType, resourceID)
+	token := strconv.FormatInt(int64(lease.ID), 10)
+
+	resp, err := b.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, token, clientv3.WithLease(lease.ID))).
+		Commit()
+	if err != nil {
+		return "", false, err
+	}
+	if !resp.Succeeded {
+		_, _ = b.client.Revoke(ctx, lease.ID)
+		return "", false, nil
+	}
+
+	return token, true, nil
+}
+
+func (b *EtcdBackend) Release(ctx context.Context, resourceType, resourceID, token string) error {
+	resp, err := b.client.Get(ctx, b.key(resourceType, resourceID))
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) == 0 || string(resp.Kvs[0].Value) != token {
+		return nil
+	}
+
+	leaseID, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return err
+	}
+	_, err = b.client.Revoke(ctx, clientv3.LeaseID(leaseID))
+	return err
+}
+
+func (b *EtcdBackend) IsHeld(ctx context.Context, resourceType, resourceID string) (bool, error) {
+	resp, err := b.client.Get(ctx, b.key(resourceType, resourceID))
+	if err != nil {
+		return false, err
+	}
+	return len(resp.Kvs) > 0, nil
+}
@@ -0,0 +1,25 @@
+package gatekeeper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeaseTTLSecondsRoundsUpSubSecondTTL(t *testing.T) {
+	cases := []struct {
+		ttl  time.Duration
+		want int64
+	}{
+		{500 * time.Millisecond, 1},
+		{0, 1},
+		{1 * time.Second, 1},
+		{1500 * time.Millisecond, 2},
+		{5 * time.Second, 5},
+	}
+
+	for _, c := range cases {
+		if got := leaseTTLSeconds(c.ttl); got != c.want {
+			t.Errorf("leaseTTLSeconds(%v) = %d, want %d", c.ttl, got, c.want)
+		}
+	}
+}
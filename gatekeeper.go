@@ -1,6 +1,7 @@
 package gatekeeper
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -20,6 +21,10 @@ type Config struct {
 	DefaultErrorStatus  int
 	DefaultErrorMessage string
 	DefaultErrorCode    string
+	Backend             LockBackend
+	AcquireTimeout      time.Duration
+	Metrics             MetricsSink
+	SweepInterval       time.Duration
 }
 
 // Option function type for functional options pattern
@@ -27,21 +32,25 @@ type Option func(*Config)
 
 // gatekeeper maintains the internal state
 type gatekeeper struct {
-	locks  map[string]map[string]time.Time
-	mutex  sync.RWMutex
-	config Config
+	mutex         sync.RWMutex
+	config        Config
+	counterMu     sync.Mutex
+	acquireCounts map[string]int64
+	stopSweep     chan struct{}
 }
 
 // singleton instance
 var instance = &gatekeeper{
-	locks: make(map[string]map[string]time.Time),
 	config: Config{
 		LockTimeout:         5 * time.Second,
 		Enabled:             true,
 		DefaultErrorStatus:  fiber.StatusTooManyRequests,
 		DefaultErrorMessage: "Resource is currently being processed",
 		DefaultErrorCode:    "RESOURCE_LOCKED",
+		Backend:             newMemoryBackend(),
+		SweepInterval:       30 * time.Second,
 	},
+	acquireCounts: make(map[string]int64),
 }
 
 // WithLockTimeout Option functions
@@ -75,76 +84,167 @@ func WithErrorCode(code string) Option {
 	}
 }
 
-// Setup configures the gatekeeper with optional configuration overrides
+// WithBackend swaps the LockBackend used to coordinate locks. The default
+// is an in-process map, which cannot coordinate across multiple Gatekeeper
+// instances; use NewRedisBackend or NewEtcdBackend to share lock state
+// across a fleet sitting behind a load balancer.
+func WithBackend(backend LockBackend) Option {
+	return func(c *Config) {
+		c.Backend = backend
+	}
+}
+
+// WithSweepInterval sets how often the background sweeper started by Setup
+// scans for and removes expired locks. It only has an effect on backends
+// that accumulate entries indefinitely (the in-process memoryBackend);
+// remote backends typically expire entries natively via their own TTL.
+func WithSweepInterval(interval time.Duration) Option {
+	return func(c *Config) {
+		c.SweepInterval = interval
+	}
+}
+
+// Setup configures the gatekeeper with optional configuration overrides and
+// (re)starts the background sweeper that garbage-collects expired locks.
+// Call Shutdown to stop the sweeper, e.g. during graceful shutdown.
 func Setup(opts ...Option) {
 	instance.mutex.Lock()
-	defer instance.mutex.Unlock()
-
 	for _, opt := range opts {
 		opt(&instance.config)
 	}
+	interval := instance.config.SweepInterval
+	instance.mutex.Unlock()
+
+	stopSweeper()
+	startSweeper(interval)
 }
 
-// TryLock attempts to acquire a lock for a resource
-func TryLock(resourceType, resourceID string) bool {
+// Shutdown stops the background sweeper started by Setup. It is safe to
+// call even if no sweeper is running.
+func Shutdown() {
+	stopSweeper()
+}
+
+// TryLock attempts to acquire the exclusive write lock for a resource; see
+// TryRLock for a shared read lock. On success it returns
+// the token that owns the lock; that same token must be passed to
+// ReleaseLock, so a caller can never release a lock it doesn't hold.
+func TryLock(ctx context.Context, resourceType, resourceID string) (token string, ok bool, err error) {
 	if !instance.config.Enabled {
-		return true
+		return "", true, nil
 	}
 
-	instance.mutex.Lock()
-	defer instance.mutex.Unlock()
-
-	// Initialize a resource type map if needed
-	if instance.locks[resourceType] == nil {
-		instance.locks[resourceType] = make(map[string]time.Time)
-	}
+	instance.mutex.RLock()
+	backend := instance.config.Backend
+	ttl := instance.config.LockTimeout
+	metrics := instance.config.Metrics
+	instance.mutex.RUnlock()
 
-	// Check if the resource is locked and if the lock has expired
-	if timestamp, exists := instance.locks[resourceType][resourceID]; exists {
-		if time.Since(timestamp) < instance.config.LockTimeout {
-			return false
+	token, ok, err = backend.TryAcquire(ctx, resourceType, resourceID, ttl)
+	if err == nil {
+		if ok {
+			instance.counterMu.Lock()
+			instance.acquireCounts[resourceType]++
+			instance.counterMu.Unlock()
+			if metrics != nil {
+				metrics.IncAcquire(resourceType)
+			}
+		} else if metrics != nil {
+			metrics.IncContended(resourceType)
 		}
 	}
-
-	// Acquire the lock
-	instance.locks[resourceType][resourceID] = time.Now()
-	return true
+	return token, ok, err
 }
 
-// ReleaseLock releases a lock on a resource
-func ReleaseLock(resourceType, resourceID string) {
+// TryLockWithMetadata is like TryLock, but additionally records metadata
+// (e.g. hostname, request ID) alongside the lock for operators to see via
+// ListLocks/AdminHandler. This requires a MetadataBackend; against a
+// backend that doesn't implement it, metadata is silently dropped and this
+// behaves exactly like TryLock.
+func TryLockWithMetadata(ctx context.Context, resourceType, resourceID string, metadata map[string]string) (token string, ok bool, err error) {
+	instance.mutex.RLock()
+	backend := instance.config.Backend
+	instance.mutex.RUnlock()
+
+	if _, supported := backend.(MetadataBackend); !supported {
+		return TryLock(ctx, resourceType, resourceID)
+	}
+
 	if !instance.config.Enabled {
-		return
+		return "", true, nil
 	}
 
-	instance.mutex.Lock()
-	defer instance.mutex.Unlock()
+	instance.mutex.RLock()
+	ttl := instance.config.LockTimeout
+	metrics := instance.config.Metrics
+	instance.mutex.RUnlock()
 
-	if locks, exists := instance.locks[resourceType]; exists {
-		delete(locks, resourceID)
+	token, ok, err = backend.(MetadataBackend).TryAcquireWithMetadata(ctx, resourceType, resourceID, ttl, metadata)
+	if err == nil {
+		if ok {
+			instance.counterMu.Lock()
+			instance.acquireCounts[resourceType]++
+			instance.counterMu.Unlock()
+			if metrics != nil {
+				metrics.IncAcquire(resourceType)
+			}
+		} else if metrics != nil {
+			metrics.IncContended(resourceType)
+		}
 	}
+	return token, ok, err
 }
 
-// IsLocked checks if a resource is currently locked
-func IsLocked(resourceType, resourceID string) bool {
+// ReleaseLock releases a lock on a resource, provided token matches the one
+// returned by the TryLock call that acquired it.
+func ReleaseLock(ctx context.Context, resourceType, resourceID, token string) error {
 	if !instance.config.Enabled {
-		return false
+		return nil
 	}
 
 	instance.mutex.RLock()
-	defer instance.mutex.RUnlock()
+	backend := instance.config.Backend
+	metrics := instance.config.Metrics
+	instance.mutex.RUnlock()
+
+	var acquiredAt time.Time
+	if metrics != nil {
+		if introspectable, ok := backend.(IntrospectableBackend); ok {
+			if locks, err := introspectable.ListLocks(ctx, resourceType); err == nil {
+				for _, lock := range locks {
+					if lock.ResourceID == resourceID && lock.Token == token {
+						acquiredAt = lock.AcquiredAt
+						break
+					}
+				}
+			}
+		}
+	}
 
-	locks, exists := instance.locks[resourceType]
-	if !exists {
-		return false
+	if err := backend.Release(ctx, resourceType, resourceID, token); err != nil {
+		return err
 	}
 
-	timestamp, exists := locks[resourceID]
-	if !exists {
-		return false
+	if metrics != nil {
+		metrics.IncReleased(resourceType)
+		if !acquiredAt.IsZero() {
+			metrics.ObserveHoldDuration(resourceType, time.Since(acquiredAt))
+		}
 	}
+	return nil
+}
 
-	return time.Since(timestamp) < instance.config.LockTimeout
+// IsLocked checks if a resource is currently locked
+func IsLocked(ctx context.Context, resourceType, resourceID string) (bool, error) {
+	if !instance.config.Enabled {
+		return false, nil
+	}
+
+	instance.mutex.RLock()
+	backend := instance.config.Backend
+	instance.mutex.RUnlock()
+
+	return backend.IsHeld(ctx, resourceType, resourceID)
 }
 
 // Middleware creates a Fiber middleware that protects routes using resource locking
@@ -159,14 +259,17 @@ func Middleware(resourceType string, idExtractor func(*fiber.Ctx) string) fiber.
 			return c.Next()
 		}
 
-		if !TryLock(resourceType, resourceID) {
+		ctx := c.UserContext()
+
+		token, ok, err := TryLock(ctx, resourceType, resourceID)
+		if err != nil || !ok {
 			return c.Status(instance.config.DefaultErrorStatus).JSON(fiber.Map{
 				"message": instance.config.DefaultErrorMessage,
 				"code":    instance.config.DefaultErrorCode,
 			})
 		}
 
-		defer ReleaseLock(resourceType, resourceID)
+		defer ReleaseLock(context.WithoutCancel(ctx), resourceType, resourceID, token)
 		return c.Next()
 	}
 }
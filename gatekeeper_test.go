@@ -0,0 +1,43 @@
+package gatekeeper
+
+import (
+	"context"
+	"testing"
+)
+
+// cancelOnReleaseBackend fails Release if the context it receives is
+// already canceled, simulating a remote backend (Redis/etcd)
+// rejecting an RPC made with a dead context.
+type cancelOnReleaseBackend struct {
+	*memoryBackend
+}
+
+func (b cancelOnReleaseBackend) Release(ctx context.Context, resourceType, resourceID, token string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.memoryBackend.Release(ctx, resourceType, resourceID, token)
+}
+
+func TestReleaseLockSurvivesCanceledContext(t *testing.T) {
+	backend := cancelOnReleaseBackend{memoryBackend: newMemoryBackend()}
+	Setup(WithBackend(backend), WithSweepInterval(0))
+	defer Shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	token, ok, err := TryLock(ctx, "user", "1")
+	if err != nil || !ok {
+		t.Fatalf("TryLock failed: ok=%v err=%v", ok, err)
+	}
+
+	cancel()
+
+	if err := ReleaseLock(context.WithoutCancel(ctx), "user", "1", token); err != nil {
+		t.Fatalf("ReleaseLock with a canceled-ancestor context failed: %v", err)
+	}
+
+	held, err := IsLocked(context.Background(), "user", "1")
+	if err != nil || held {
+		t.Fatalf("lock still held after release: held=%v err=%v", held, err)
+	}
+}
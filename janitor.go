@@ -0,0 +1,80 @@
+package gatekeeper
+
+import (
+	"context"
+	"time"
+)
+
+// sweepableBackend is implemented by backends that accumulate lock entries
+// indefinitely and need periodic garbage collection. memoryBackend is the
+// only one that needs this: Redis and etcd expire entries natively via
+// their own TTL/lease mechanism.
+type sweepableBackend interface {
+	// sweepExpired removes expired entries and returns how many were
+	// removed, keyed by resource type, so callers can report expiry
+	// metrics with the same per-type granularity as every other counter.
+	sweepExpired(ctx context.Context) (removed map[string]int, err error)
+}
+
+// startSweeper launches the background janitor goroutine if interval > 0.
+// It records the stop channel on instance so a later stopSweeper call can
+// shut it down.
+func startSweeper(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+
+	instance.mutex.Lock()
+	instance.stopSweep = stop
+	instance.mutex.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				sweepOnce()
+			}
+		}
+	}()
+}
+
+// stopSweeper stops the currently running sweeper goroutine, if any.
+func stopSweeper() {
+	instance.mutex.Lock()
+	stop := instance.stopSweep
+	instance.stopSweep = nil
+	instance.mutex.Unlock()
+
+	if stop != nil {
+		close(stop)
+	}
+}
+
+func sweepOnce() {
+	instance.mutex.RLock()
+	backend := instance.config.Backend
+	metrics := instance.config.Metrics
+	instance.mutex.RUnlock()
+
+	sweepable, ok := backend.(sweepableBackend)
+	if !ok {
+		return
+	}
+
+	removed, err := sweepable.sweepExpired(context.Background())
+	if err != nil || metrics == nil {
+		return
+	}
+	for resourceType, count := range removed {
+		for i := 0; i < count; i++ {
+			metrics.IncExpired(resourceType)
+		}
+	}
+}
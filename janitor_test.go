@@ -0,0 +1,61 @@
+package gatekeeper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type perTypeMetricsSink struct {
+	expiredByType map[string]int
+}
+
+func (f *perTypeMetricsSink) IncAcquire(resourceType string)   {}
+func (f *perTypeMetricsSink) IncContended(resourceType string) {}
+func (f *perTypeMetricsSink) IncReleased(resourceType string)  {}
+func (f *perTypeMetricsSink) IncExpired(resourceType string) {
+	if f.expiredByType == nil {
+		f.expiredByType = make(map[string]int)
+	}
+	f.expiredByType[resourceType]++
+}
+func (f *perTypeMetricsSink) ObserveHoldDuration(string, time.Duration) {}
+
+func TestSweepOnceReportsExpiryMetricsPerResourceType(t *testing.T) {
+	sink := &perTypeMetricsSink{}
+	Setup(WithBackend(newMemoryBackend()), WithMetrics(sink), WithSweepInterval(0))
+	defer Shutdown()
+
+	ctx := context.Background()
+	if _, _, err := TryLock(ctx, "user", "1"); err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+	if _, _, err := TryLock(ctx, "order", "1"); err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+	if _, _, err := TryLock(ctx, "order", "2"); err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+
+	instance.mutex.RLock()
+	backend := instance.config.Backend
+	instance.mutex.RUnlock()
+	sweepable := backend.(*memoryBackend)
+	sweepable.mutex.Lock()
+	for _, locks := range sweepable.locks {
+		for _, state := range locks {
+			state.writerAt = time.Now().Add(-time.Hour)
+			state.writerTTL = time.Millisecond
+		}
+	}
+	sweepable.mutex.Unlock()
+
+	sweepOnce()
+
+	if sink.expiredByType["user"] != 1 {
+		t.Fatalf("expiredByType[user] = %d, want 1", sink.expiredByType["user"])
+	}
+	if sink.expiredByType["order"] != 2 {
+		t.Fatalf("expiredByType[order] = %d, want 2", sink.expiredByType["order"])
+	}
+}
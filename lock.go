@@ -0,0 +1,116 @@
+package gatekeeper
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	minBackoff = 5 * time.Millisecond
+	maxBackoff = 50 * time.Millisecond
+)
+
+// ReleaseFunc releases a lock acquired by Lock. It is safe to call exactly
+// once; the lock handle itself is never exposed, so callers cannot
+// double-release it or reuse it after release.
+type ReleaseFunc func()
+
+// Lock blocks until the lock for (resourceType, resourceID) is acquired,
+// ctx is canceled, or WithAcquireTimeout's budget elapses, whichever comes
+// first. It retries TryLock with a bounded, jittered backoff between
+// minBackoff and maxBackoff so a contended resource doesn't busy-spin, and
+// it checks ctx.Done() between attempts so a caller-canceled ctx aborts the
+// wait immediately instead of leaking a goroutine. Note that fasthttp's
+// *fiber.Ctx.UserContext() is NOT canceled when the client disconnects
+// (fasthttp.RequestCtx only cancels its context on server shutdown), so in
+// MiddlewareBlocking the only thing that ends a wait on a stuck lock is
+// WithAcquireTimeout; set one if you want a bound on wait time.
+func Lock(ctx context.Context, resourceType, resourceID string) (ReleaseFunc, error) {
+	if !instance.config.Enabled {
+		return func() {}, nil
+	}
+
+	instance.mutex.RLock()
+	acquireTimeout := instance.config.AcquireTimeout
+	instance.mutex.RUnlock()
+
+	if acquireTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, acquireTimeout)
+		defer cancel()
+	}
+
+	backoff := minBackoff
+	for {
+		token, ok, err := TryLock(ctx, resourceType, resourceID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			releaseCtx := context.WithoutCancel(ctx)
+			return func() {
+				_ = ReleaseLock(releaseCtx, resourceType, resourceID, token)
+			}, nil
+		}
+
+		jittered := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2+1)))
+		timer := time.NewTimer(jittered)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if backoff < maxBackoff {
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+	}
+}
+
+// WithAcquireTimeout bounds how long Lock (and MiddlewareBlocking) will
+// wait for a contended resource before giving up, independent of the
+// caller's context deadline. Zero (the default) means wait as long as ctx
+// allows.
+func WithAcquireTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.AcquireTimeout = timeout
+	}
+}
+
+// MiddlewareBlocking is like Middleware, but instead of immediately
+// responding with the locked error it waits (per Lock's semantics) for the
+// resource to become free, falling back to the standard 429 payload if
+// WithAcquireTimeout's budget elapses first. Always configure
+// WithAcquireTimeout alongside this middleware: since a disconnecting
+// client does not cancel c.UserContext() (see Lock's doc comment), an
+// unbounded wait here waits as long as the resource stays locked.
+func MiddlewareBlocking(resourceType string, idExtractor func(*fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !instance.config.Enabled {
+			return c.Next()
+		}
+
+		resourceID := idExtractor(c)
+		if resourceID == "" {
+			return c.Next()
+		}
+
+		release, err := Lock(c.UserContext(), resourceType, resourceID)
+		if err != nil {
+			return c.Status(instance.config.DefaultErrorStatus).JSON(fiber.Map{
+				"message": instance.config.DefaultErrorMessage,
+				"code":    instance.config.DefaultErrorCode,
+			})
+		}
+
+		defer release()
+		return c.Next()
+	}
+}
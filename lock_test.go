@@ -0,0 +1,90 @@
+package gatekeeper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLockBlocksUntilConcurrentRelease(t *testing.T) {
+	Setup(WithBackend(newMemoryBackend()), WithLockTimeout(time.Second), WithSweepInterval(0))
+	defer Shutdown()
+
+	ctx := context.Background()
+	token, ok, err := TryLock(ctx, "user", "1")
+	if err != nil || !ok {
+		t.Fatalf("initial TryLock failed: ok=%v err=%v", ok, err)
+	}
+
+	releasedAt := make(chan time.Time, 1)
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		releasedAt <- time.Now()
+		_ = ReleaseLock(ctx, "user", "1", token)
+	}()
+
+	start := time.Now()
+	release, err := Lock(ctx, "user", "1")
+	if err != nil {
+		t.Fatalf("Lock returned error: %v", err)
+	}
+	defer release()
+
+	acquiredAfterRelease := time.Now().After(<-releasedAt)
+	if !acquiredAfterRelease {
+		t.Fatalf("Lock returned before the concurrent release happened")
+	}
+	if time.Since(start) < 30*time.Millisecond {
+		t.Fatalf("Lock returned suspiciously fast (%v), did it actually wait?", time.Since(start))
+	}
+}
+
+func TestLockReturnsContextErrOnCancel(t *testing.T) {
+	Setup(WithBackend(newMemoryBackend()), WithLockTimeout(time.Minute), WithSweepInterval(0))
+	defer Shutdown()
+
+	ctx := context.Background()
+	if _, ok, err := TryLock(ctx, "user", "1"); err != nil || !ok {
+		t.Fatalf("initial TryLock failed: ok=%v err=%v", ok, err)
+	}
+
+	waitCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := Lock(waitCtx, "user", "1")
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Lock error = %v, want context.Canceled", err)
+	}
+	if elapsed > 200*time.Millisecond {
+		t.Fatalf("Lock took %v to notice cancellation, want prompt return", elapsed)
+	}
+}
+
+func TestLockWithAcquireTimeoutExpiresWithoutHanging(t *testing.T) {
+	Setup(WithBackend(newMemoryBackend()), WithLockTimeout(time.Minute), WithAcquireTimeout(30*time.Millisecond), WithSweepInterval(0))
+	defer Setup(WithAcquireTimeout(0))
+	defer Shutdown()
+
+	ctx := context.Background()
+	if _, ok, err := TryLock(ctx, "user", "1"); err != nil || !ok {
+		t.Fatalf("initial TryLock failed: ok=%v err=%v", ok, err)
+	}
+
+	start := time.Now()
+	_, err := Lock(ctx, "user", "1")
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Lock error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed > 300*time.Millisecond {
+		t.Fatalf("Lock took %v to give up, want it bounded by WithAcquireTimeout", elapsed)
+	}
+}
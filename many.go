@@ -0,0 +1,92 @@
+package gatekeeper
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ResourceRef identifies a single resource to lock, used by TryLockMany and
+// MiddlewareMany for operations that touch more than one resource at once.
+type ResourceRef struct {
+	ResourceType string
+	ResourceID   string
+}
+
+// TryLockMany acquires locks for all of pairs as a single atomic operation:
+// either every lock is acquired, or none are left held. Pairs are sorted by
+// (ResourceType, ResourceID) before acquisition so two callers racing over
+// the same set of resources in different orders (e.g. {A,B} vs {B,A})
+// always acquire them in the same order and cannot deadlock each other. If
+// any acquisition fails, every lock acquired so far is released before
+// returning.
+func TryLockMany(ctx context.Context, pairs []ResourceRef) (acquired bool, released func()) {
+	ordered := make([]ResourceRef, len(pairs))
+	copy(ordered, pairs)
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].ResourceType != ordered[j].ResourceType {
+			return ordered[i].ResourceType < ordered[j].ResourceType
+		}
+		return ordered[i].ResourceID < ordered[j].ResourceID
+	})
+
+	type held struct {
+		ref   ResourceRef
+		token string
+	}
+	holdings := make([]held, 0, len(ordered))
+
+	// Release must not be canceled along with ctx: if ctx is canceled
+	// right as we're rolling back a partial acquisition, or by the time
+	// the caller invokes the returned released func, a canceled ctx would
+	// make a remote backend's Release fail immediately and leak the lock
+	// until its TTL.
+	releaseCtx := context.WithoutCancel(ctx)
+	releaseHeld := func() {
+		for i := len(holdings) - 1; i >= 0; i-- {
+			h := holdings[i]
+			_ = ReleaseLock(releaseCtx, h.ref.ResourceType, h.ref.ResourceID, h.token)
+		}
+	}
+
+	for _, ref := range ordered {
+		token, ok, err := TryLock(ctx, ref.ResourceType, ref.ResourceID)
+		if err != nil || !ok {
+			releaseHeld()
+			return false, func() {}
+		}
+		holdings = append(holdings, held{ref: ref, token: token})
+	}
+
+	return true, releaseHeld
+}
+
+// MiddlewareMany is like Middleware, but for routes whose handler mutates
+// several resources at once (e.g. a transfer touching two user IDs, or a
+// batch delete). extractor returns the full set of resources the request
+// will touch; the request proceeds only if all of them can be locked
+// together.
+func MiddlewareMany(extractor func(*fiber.Ctx) []ResourceRef) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !instance.config.Enabled {
+			return c.Next()
+		}
+
+		refs := extractor(c)
+		if len(refs) == 0 {
+			return c.Next()
+		}
+
+		acquired, released := TryLockMany(c.UserContext(), refs)
+		if !acquired {
+			return c.Status(instance.config.DefaultErrorStatus).JSON(fiber.Map{
+				"message": instance.config.DefaultErrorMessage,
+				"code":    instance.config.DefaultErrorCode,
+			})
+		}
+
+		defer released()
+		return c.Next()
+	}
+}
@@ -0,0 +1,98 @@
+package gatekeeper
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// orderRecordingBackend wraps memoryBackend and records the order in which
+// TryAcquire is called, so tests can assert on acquisition ordering without
+// depending on timing.
+type orderRecordingBackend struct {
+	*memoryBackend
+	mu    sync.Mutex
+	order []ResourceRef
+}
+
+func newOrderRecordingBackend() *orderRecordingBackend {
+	return &orderRecordingBackend{memoryBackend: newMemoryBackend()}
+}
+
+func (b *orderRecordingBackend) TryAcquire(ctx context.Context, resourceType, resourceID string, ttl time.Duration) (string, bool, error) {
+	b.mu.Lock()
+	b.order = append(b.order, ResourceRef{ResourceType: resourceType, ResourceID: resourceID})
+	b.mu.Unlock()
+	return b.memoryBackend.TryAcquire(ctx, resourceType, resourceID, ttl)
+}
+
+func TestTryLockManyRollsBackOnPartialFailure(t *testing.T) {
+	Setup(WithBackend(newMemoryBackend()), WithSweepInterval(0))
+	defer Shutdown()
+
+	ctx := context.Background()
+
+	// Pre-lock "user/2", which sorts after "user/1", so TryLockMany
+	// should acquire user/1 first and then fail on the already-held
+	// user/2.
+	preToken, ok, err := TryLock(ctx, "user", "2")
+	if err != nil || !ok {
+		t.Fatalf("pre-lock failed: ok=%v err=%v", ok, err)
+	}
+
+	acquired, released := TryLockMany(ctx, []ResourceRef{
+		{ResourceType: "user", ResourceID: "1"},
+		{ResourceType: "user", ResourceID: "2"},
+	})
+	if acquired {
+		t.Fatalf("TryLockMany acquired = true, want false (user/2 is already held)")
+	}
+	released()
+
+	held, err := IsLocked(ctx, "user", "1")
+	if err != nil || held {
+		t.Fatalf("user/1 still held after rollback: held=%v err=%v", held, err)
+	}
+
+	held, err = IsLocked(ctx, "user", "2")
+	if err != nil || !held {
+		t.Fatalf("user/2 should still be held by the original pre-locker: held=%v err=%v", held, err)
+	}
+
+	if err := ReleaseLock(ctx, "user", "2", preToken); err != nil {
+		t.Fatalf("cleanup ReleaseLock: %v", err)
+	}
+}
+
+func TestTryLockManySortsRegardlessOfInputOrder(t *testing.T) {
+	ctx := context.Background()
+
+	backendA := newOrderRecordingBackend()
+	Setup(WithBackend(backendA), WithSweepInterval(0))
+	_, releasedA := TryLockMany(ctx, []ResourceRef{
+		{ResourceType: "user", ResourceID: "2"},
+		{ResourceType: "user", ResourceID: "1"},
+	})
+	releasedA()
+
+	backendB := newOrderRecordingBackend()
+	Setup(WithBackend(backendB), WithSweepInterval(0))
+	_, releasedB := TryLockMany(ctx, []ResourceRef{
+		{ResourceType: "user", ResourceID: "1"},
+		{ResourceType: "user", ResourceID: "2"},
+	})
+	releasedB()
+	defer Shutdown()
+
+	want := []ResourceRef{
+		{ResourceType: "user", ResourceID: "1"},
+		{ResourceType: "user", ResourceID: "2"},
+	}
+	if len(backendA.order) != 2 || backendA.order[0] != want[0] || backendA.order[1] != want[1] {
+		t.Fatalf("backendA acquire order = %v, want %v", backendA.order, want)
+	}
+	if len(backendB.order) != 2 || backendB.order[0] != want[0] || backendB.order[1] != want[1] {
+		t.Fatalf("backendB acquire order = %v, want %v", backendB.order, want)
+	}
+}
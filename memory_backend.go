@@ -0,0 +1,236 @@
+package gatekeeper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// readerHold records a single reader's acquisition, keyed by its token.
+type readerHold struct {
+	timestamp time.Time
+	ttl       time.Duration
+}
+
+// memoryLockState is the per-(resourceType, resourceID) lock state. A
+// resource has either a single active writer or any number of active
+// readers, never both: TryAcquire (write) requires no active writer and no
+// active readers, while TryAcquireRead requires only no active writer.
+type memoryLockState struct {
+	writer      bool
+	writerToken string
+	writerAt    time.Time
+	writerTTL   time.Duration
+	writerMeta  map[string]string
+	readers     map[string]readerHold
+}
+
+func (s *memoryLockState) writerActive() bool {
+	return s.writer && time.Since(s.writerAt) < s.writerTTL
+}
+
+func (s *memoryLockState) hasActiveReader() bool {
+	for _, r := range s.readers {
+		if time.Since(r.timestamp) < r.ttl {
+			return true
+		}
+	}
+	return false
+}
+
+// memoryBackend is the default LockBackend: an in-process map. It is the
+// original Gatekeeper behavior extracted behind the LockBackend interface,
+// and it cannot coordinate across more than one process.
+type memoryBackend struct {
+	mutex sync.Mutex
+	locks map[string]map[string]*memoryLockState
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{
+		locks: make(map[string]map[string]*memoryLockState),
+	}
+}
+
+func (b *memoryBackend) stateFor(resourceType, resourceID string) *memoryLockState {
+	if b.locks[resourceType] == nil {
+		b.locks[resourceType] = make(map[string]*memoryLockState)
+	}
+	state, exists := b.locks[resourceType][resourceID]
+	if !exists {
+		state = &memoryLockState{readers: make(map[string]readerHold)}
+		b.locks[resourceType][resourceID] = state
+	}
+	return state
+}
+
+func (b *memoryBackend) TryAcquire(ctx context.Context, resourceType, resourceID string, ttl time.Duration) (string, bool, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	state := b.stateFor(resourceType, resourceID)
+	if state.writerActive() || state.hasActiveReader() {
+		return "", false, nil
+	}
+
+	token := newToken()
+	state.writer = true
+	state.writerToken = token
+	state.writerAt = time.Now()
+	state.writerTTL = ttl
+	state.writerMeta = nil
+	return token, true, nil
+}
+
+func (b *memoryBackend) TryAcquireWithMetadata(ctx context.Context, resourceType, resourceID string, ttl time.Duration, metadata map[string]string) (string, bool, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	state := b.stateFor(resourceType, resourceID)
+	if state.writerActive() || state.hasActiveReader() {
+		return "", false, nil
+	}
+
+	token := newToken()
+	state.writer = true
+	state.writerToken = token
+	state.writerAt = time.Now()
+	state.writerTTL = ttl
+	state.writerMeta = metadata
+	return token, true, nil
+}
+
+func (b *memoryBackend) Release(ctx context.Context, resourceType, resourceID, token string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	locks, exists := b.locks[resourceType]
+	if !exists {
+		return nil
+	}
+
+	if state, exists := locks[resourceID]; exists && state.writer && state.writerToken == token {
+		state.writer = false
+		state.writerToken = ""
+	}
+	return nil
+}
+
+func (b *memoryBackend) IsHeld(ctx context.Context, resourceType, resourceID string) (bool, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	locks, exists := b.locks[resourceType]
+	if !exists {
+		return false, nil
+	}
+
+	state, exists := locks[resourceID]
+	if !exists {
+		return false, nil
+	}
+	return state.writerActive() || state.hasActiveReader(), nil
+}
+
+func (b *memoryBackend) ListLocks(ctx context.Context, resourceType string) ([]LockInfo, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var infos []LockInfo
+	for rt, locks := range b.locks {
+		if resourceType != "" && rt != resourceType {
+			continue
+		}
+		for id, state := range locks {
+			if state.writerActive() {
+				infos = append(infos, LockInfo{
+					ResourceType: rt,
+					ResourceID:   id,
+					Token:        state.writerToken,
+					AcquiredAt:   state.writerAt,
+					TTLRemaining: state.writerTTL - time.Since(state.writerAt),
+					Metadata:     state.writerMeta,
+				})
+			}
+			for token, reader := range state.readers {
+				if time.Since(reader.timestamp) >= reader.ttl {
+					continue
+				}
+				infos = append(infos, LockInfo{
+					ResourceType: rt,
+					ResourceID:   id,
+					Token:        token,
+					AcquiredAt:   reader.timestamp,
+					TTLRemaining: reader.ttl - time.Since(reader.timestamp),
+				})
+			}
+		}
+	}
+	return infos, nil
+}
+
+func (b *memoryBackend) sweepExpired(ctx context.Context) (map[string]int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	removed := make(map[string]int)
+	for resourceType, locks := range b.locks {
+		for id, state := range locks {
+			if state.writer && !state.writerActive() {
+				state.writer = false
+				state.writerToken = ""
+				removed[resourceType]++
+			}
+			for token, reader := range state.readers {
+				if time.Since(reader.timestamp) >= reader.ttl {
+					delete(state.readers, token)
+					removed[resourceType]++
+				}
+			}
+			if !state.writer && len(state.readers) == 0 {
+				delete(locks, id)
+			}
+		}
+	}
+	return removed, nil
+}
+
+func (b *memoryBackend) ForceRelease(ctx context.Context, resourceType, resourceID string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	locks, exists := b.locks[resourceType]
+	if !exists {
+		return nil
+	}
+	delete(locks, resourceID)
+	return nil
+}
+
+func (b *memoryBackend) TryAcquireRead(ctx context.Context, resourceType, resourceID string, ttl time.Duration) (string, bool, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	state := b.stateFor(resourceType, resourceID)
+	if state.writerActive() {
+		return "", false, nil
+	}
+
+	token := newToken()
+	state.readers[token] = readerHold{timestamp: time.Now(), ttl: ttl}
+	return token, true, nil
+}
+
+func (b *memoryBackend) ReleaseRead(ctx context.Context, resourceType, resourceID, token string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	locks, exists := b.locks[resourceType]
+	if !exists {
+		return nil
+	}
+	if state, exists := locks[resourceID]; exists {
+		delete(state.readers, token)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package gatekeeper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryBackendSweepExpiredRemovesResidentEntries(t *testing.T) {
+	b := newMemoryBackend()
+	ctx := context.Background()
+
+	for i := 0; i < 1000; i++ {
+		id := string(rune('a' + i%26))
+		if _, _, err := b.TryAcquire(ctx, "user", id+string(rune(i)), time.Millisecond); err != nil {
+			t.Fatalf("TryAcquire: %v", err)
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	removed, err := b.sweepExpired(ctx)
+	if err != nil {
+		t.Fatalf("sweepExpired: %v", err)
+	}
+	if removed["user"] != 1000 {
+		t.Fatalf("removed[user] = %d, want 1000", removed["user"])
+	}
+
+	b.mutex.Lock()
+	resident := len(b.locks["user"])
+	b.mutex.Unlock()
+
+	if resident != 0 {
+		t.Fatalf("resident entries after sweep = %d, want 0 (expired locks must not accumulate)", resident)
+	}
+}
+
+func TestMemoryBackendTokenGatedRelease(t *testing.T) {
+	b := newMemoryBackend()
+	ctx := context.Background()
+
+	token, ok, err := b.TryAcquire(ctx, "user", "1", time.Second)
+	if err != nil || !ok {
+		t.Fatalf("TryAcquire failed: ok=%v err=%v", ok, err)
+	}
+
+	if err := b.Release(ctx, "user", "1", "not-the-real-token"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	held, err := b.IsHeld(ctx, "user", "1")
+	if err != nil || !held {
+		t.Fatalf("lock released by wrong token: held=%v err=%v", held, err)
+	}
+
+	if err := b.Release(ctx, "user", "1", token); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	held, err = b.IsHeld(ctx, "user", "1")
+	if err != nil || held {
+		t.Fatalf("lock still held after correct-token release: held=%v err=%v", held, err)
+	}
+}
@@ -0,0 +1,23 @@
+package gatekeeper
+
+import "time"
+
+// MetricsSink lets callers wire Gatekeeper's lock activity into their own
+// metrics system (Prometheus, Datadog, ...) without this module importing
+// a metrics library itself.
+type MetricsSink interface {
+	IncAcquire(resourceType string)
+	IncContended(resourceType string)
+	IncReleased(resourceType string)
+	IncExpired(resourceType string)
+	ObserveHoldDuration(resourceType string, d time.Duration)
+}
+
+// WithMetrics registers a MetricsSink that receives lock acquire/contend/
+// release/expire events. It's optional; with none configured (the
+// default) Gatekeeper does no metrics work beyond its own Stats counters.
+func WithMetrics(sink MetricsSink) Option {
+	return func(c *Config) {
+		c.Metrics = sink
+	}
+}
@@ -0,0 +1,69 @@
+package gatekeeper
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseScript deletes the lock key only if it still holds the token we
+// acquired it with, so a caller can never release a lock it doesn't own
+// (e.g. one that expired and was re-acquired by someone else).
+const releaseScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+else
+	return 0
+end
+`
+
+// RedisBackend is a LockBackend that coordinates across processes using a
+// shared Redis instance. Acquisition uses SET NX PX for an atomic
+// check-and-set with a server-side TTL; release uses a Lua script so the
+// compare-token-then-delete is also atomic.
+type RedisBackend struct {
+	client *redis.Client
+	script *redis.Script
+}
+
+// NewRedisBackend wraps an existing *redis.Client as a LockBackend.
+func NewRedisBackend(client *redis.Client) *RedisBackend {
+	return &RedisBackend{
+		client: client,
+		script: redis.NewScript(releaseScript),
+	}
+}
+
+func (b *RedisBackend) key(resourceType, resourceID string) string {
+	return "gatekeeper:" + resourceType + ":" + resourceID
+}
+
+func (b *RedisBackend) TryAcquire(ctx context.Context, resourceType, resourceID string, ttl time.Duration) (string, bool, error) {
+	token := newToken()
+	ok, err := b.client.SetNX(ctx, b.key(resourceType, resourceID), token, ttl).Result()
+	if err != nil {
+		return "", false, err
+	}
+	if !ok {
+		return "", false, nil
+	}
+	return token, true, nil
+}
+
+func (b *RedisBackend) Release(ctx context.Context, resourceType, resourceID, token string) error {
+	err := b.script.Run(ctx, b.client, []string{b.key(resourceType, resourceID)}, token).Err()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+func (b *RedisBackend) IsHeld(ctx context.Context, resourceType, resourceID string) (bool, error) {
+	n, err := b.client.Exists(ctx, b.key(resourceType, resourceID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,153 @@
+package gatekeeper
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrRWUnsupported is returned by TryRLock and RUnlock when the configured
+// Backend doesn't implement RWLockBackend.
+var ErrRWUnsupported = errors.New("gatekeeper: backend does not support read/write locking")
+
+// RWLockBackend is implemented by backends that support shared read locks
+// alongside the exclusive write lock from LockBackend. It's optional:
+// memoryBackend implements it, but a remote backend isn't required to.
+type RWLockBackend interface {
+	// TryAcquireRead takes a shared read hold on (resourceType,
+	// resourceID), succeeding as long as there is no active writer. Any
+	// number of readers may hold the resource concurrently.
+	TryAcquireRead(ctx context.Context, resourceType, resourceID string, ttl time.Duration) (token string, ok bool, err error)
+
+	// ReleaseRead gives up a read hold acquired by TryAcquireRead.
+	ReleaseRead(ctx context.Context, resourceType, resourceID, token string) error
+}
+
+// LockMode selects whether MiddlewareRW takes a shared read lock or the
+// exclusive write lock for a route.
+type LockMode int
+
+const (
+	// LockModeRead takes a shared read lock: it succeeds alongside other
+	// readers, but blocks while a writer holds the resource.
+	LockModeRead LockMode = iota
+	// LockModeWrite takes the exclusive write lock, identical to
+	// Middleware/TryLock.
+	LockModeWrite
+)
+
+// TryRLock attempts to acquire a shared read lock for a resource,
+// succeeding as long as no writer currently holds it (other readers do not
+// block it). As with TryLock, the returned token must be passed to
+// RUnlock.
+func TryRLock(ctx context.Context, resourceType, resourceID string) (token string, ok bool, err error) {
+	if !instance.config.Enabled {
+		return "", true, nil
+	}
+
+	instance.mutex.RLock()
+	backend := instance.config.Backend
+	ttl := instance.config.LockTimeout
+	metrics := instance.config.Metrics
+	instance.mutex.RUnlock()
+
+	rwBackend, supported := backend.(RWLockBackend)
+	if !supported {
+		return "", false, ErrRWUnsupported
+	}
+
+	token, ok, err = rwBackend.TryAcquireRead(ctx, resourceType, resourceID, ttl)
+	if err == nil {
+		if ok {
+			instance.counterMu.Lock()
+			instance.acquireCounts[resourceType]++
+			instance.counterMu.Unlock()
+			if metrics != nil {
+				metrics.IncAcquire(resourceType)
+			}
+		} else if metrics != nil {
+			metrics.IncContended(resourceType)
+		}
+	}
+	return token, ok, err
+}
+
+// RUnlock releases a read lock acquired by TryRLock.
+func RUnlock(ctx context.Context, resourceType, resourceID, token string) error {
+	if !instance.config.Enabled {
+		return nil
+	}
+
+	instance.mutex.RLock()
+	backend := instance.config.Backend
+	metrics := instance.config.Metrics
+	instance.mutex.RUnlock()
+
+	rwBackend, supported := backend.(RWLockBackend)
+	if !supported {
+		return ErrRWUnsupported
+	}
+
+	var acquiredAt time.Time
+	if metrics != nil {
+		if introspectable, ok := backend.(IntrospectableBackend); ok {
+			if locks, err := introspectable.ListLocks(ctx, resourceType); err == nil {
+				for _, lock := range locks {
+					if lock.ResourceID == resourceID && lock.Token == token {
+						acquiredAt = lock.AcquiredAt
+						break
+					}
+				}
+			}
+		}
+	}
+
+	if err := rwBackend.ReleaseRead(ctx, resourceType, resourceID, token); err != nil {
+		return err
+	}
+
+	if metrics != nil {
+		metrics.IncReleased(resourceType)
+		if !acquiredAt.IsZero() {
+			metrics.ObserveHoldDuration(resourceType, time.Since(acquiredAt))
+		}
+	}
+	return nil
+}
+
+// MiddlewareRW is like Middleware, but takes a shared read lock instead of
+// the exclusive write lock when mode is LockModeRead. This unblocks the
+// common REST pattern where many concurrent reads to e.g. GET /users/:id
+// shouldn't serialize behind each other, but must still block while a
+// PATCH /users/:id write handler holds the resource.
+func MiddlewareRW(resourceType string, mode LockMode, idExtractor func(*fiber.Ctx) string) fiber.Handler {
+	if mode == LockModeWrite {
+		return Middleware(resourceType, idExtractor)
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !instance.config.Enabled {
+			return c.Next()
+		}
+
+		resourceID := idExtractor(c)
+		if resourceID == "" {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+
+		token, ok, err := TryRLock(ctx, resourceType, resourceID)
+		if err != nil || !ok {
+			return c.Status(instance.config.DefaultErrorStatus).JSON(fiber.Map{
+				"message": instance.config.DefaultErrorMessage,
+				"code":    instance.config.DefaultErrorCode,
+			})
+		}
+
+		defer RUnlock(context.WithoutCancel(ctx), resourceType, resourceID, token)
+		return c.Next()
+	}
+}
@@ -0,0 +1,48 @@
+package gatekeeper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeMetricsSink struct {
+	acquired, contended, released, expired int
+}
+
+func (f *fakeMetricsSink) IncAcquire(resourceType string)            { f.acquired++ }
+func (f *fakeMetricsSink) IncContended(resourceType string)          { f.contended++ }
+func (f *fakeMetricsSink) IncReleased(resourceType string)           { f.released++ }
+func (f *fakeMetricsSink) IncExpired(resourceType string)            { f.expired++ }
+func (f *fakeMetricsSink) ObserveHoldDuration(string, time.Duration) {}
+
+func TestTryRLockCountsAcquisitionsAndMetrics(t *testing.T) {
+	sink := &fakeMetricsSink{}
+	Setup(WithBackend(newMemoryBackend()), WithMetrics(sink), WithSweepInterval(0))
+	defer Shutdown()
+
+	ctx := context.Background()
+	token, ok, err := TryRLock(ctx, "user", "1")
+	if err != nil || !ok {
+		t.Fatalf("TryRLock failed: ok=%v err=%v", ok, err)
+	}
+	if sink.acquired != 1 {
+		t.Fatalf("sink.acquired = %d, want 1", sink.acquired)
+	}
+
+	before := GetStats(ctx).AcquiredByResourceType["user"]
+	if _, ok, err := TryRLock(ctx, "user", "2"); err != nil || !ok {
+		t.Fatalf("second TryRLock failed: ok=%v err=%v", ok, err)
+	}
+	after := GetStats(ctx).AcquiredByResourceType["user"]
+	if after != before+1 {
+		t.Fatalf("AcquiredByResourceType[user] went from %d to %d, want +1", before, after)
+	}
+
+	if err := RUnlock(ctx, "user", "1", token); err != nil {
+		t.Fatalf("RUnlock: %v", err)
+	}
+	if sink.released != 1 {
+		t.Fatalf("sink.released = %d, want 1", sink.released)
+	}
+}
@@ -0,0 +1,18 @@
+package gatekeeper
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newToken generates a random lock ownership token. It is used by backends
+// that don't have a more natural notion of a token (e.g. an etcd lease ID).
+func newToken() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand.Read only fails if the system CSPRNG is broken,
+		// which leaves nothing sane to do but panic.
+		panic("gatekeeper: failed to generate lock token: " + err.Error())
+	}
+	return hex.EncodeToString(buf)
+}